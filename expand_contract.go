@@ -0,0 +1,322 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// Errors that can be returned by the expand/contract commands.
+var (
+	ErrNoSuchExpandContractMigration = errors.New("no such expand/contract migration is registered")
+	ErrAlreadyActive                 = errors.New("an expand/contract migration is already active")
+	ErrNotActive                     = errors.New("that expand/contract migration is not active")
+)
+
+// ExpandContractPhases groups the three steps of a zero-downtime migration, as
+// popularized by tools like pgroll. Expand makes backward-compatible
+// additions to the schema so that code running the old and new schema can
+// both operate; Backfill copies data into whatever Expand added; Contract
+// drops whatever the old schema no longer needs. AbortExpand undoes Expand
+// and is run by the abort command if a migration needs to be backed out
+// before it's completed.
+type ExpandContractPhases struct {
+	Expand      func(orm.DB) error
+	Backfill    func(orm.DB) error
+	Contract    func(orm.DB) error
+	AbortExpand func(orm.DB) error
+}
+
+type expandContractMigration struct {
+	tableName struct{} `sql:"pg_migrations,alias:pg_migrations"`
+
+	Name        string `sql:",pk"`
+	ParentName  *string
+	Active      bool `pg:",use_zero" sql:",notnull"`
+	StartedAt   time.Time
+	CompletedAt time.Time
+}
+
+type registeredExpandContract struct {
+	phases             ExpandContractPhases
+	disableTransaction bool
+}
+
+var expandContractMigrations = map[string]registeredExpandContract{}
+
+// RegisterExpandContract adds an expand/contract migration to the registry,
+// alongside the existing Register. Unlike a regular migration, it isn't run
+// automatically by migrate; it's driven through start, complete, and abort so
+// that the Expand and Contract phases can straddle a deploy.
+//
+// opts.DisableTransaction is honored the same way Register's is: set it when
+// a phase needs to run outside a transaction, e.g. CREATE INDEX CONCURRENTLY
+// in Expand, which Postgres refuses to run inside one.
+func RegisterExpandContract(name string, phases ExpandContractPhases, opts MigrationOptions) {
+	expandContractMigrations[name] = registeredExpandContract{
+		phases:             phases,
+		disableTransaction: opts.DisableTransaction,
+	}
+}
+
+func ensureExpandContractTable(db *pg.DB) error {
+	exists, err := checkIfTableExists("pg_migrations", db)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	opts := &orm.CreateTableOptions{IfNotExists: true}
+	err = db.Model(&expandContractMigration{}).CreateTable(opts)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	// history is linear: a given migration can only be the parent of one
+	// other migration...
+	_, err = db.ExecContext(ctx,
+		`CREATE UNIQUE INDEX IF NOT EXISTS pg_migrations_parent_name_idx ON pg_migrations (parent_name)`)
+	if err != nil {
+		return err
+	}
+
+	// ...and only the very first migration may have no parent. Postgres
+	// treats NULLs as distinct for uniqueness purposes, so the plain index
+	// above doesn't stop multiple NULL-parent roots; enforce that separately.
+	_, err = db.ExecContext(ctx,
+		`CREATE UNIQUE INDEX IF NOT EXISTS pg_migrations_root_idx ON pg_migrations ((true)) WHERE parent_name IS NULL`)
+	if err != nil {
+		return err
+	}
+
+	// only one migration can be in the active/expanded window at a time.
+	_, err = db.ExecContext(ctx,
+		`CREATE UNIQUE INDEX IF NOT EXISTS pg_migrations_one_active_idx ON pg_migrations ((active)) WHERE active`)
+	return err
+}
+
+func lastExpandContractMigration(db orm.DB) (*expandContractMigration, error) {
+	exists, err := checkIfTableExists("pg_migrations", db)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	var m expandContractMigration
+	err = db.Model(&m).Order("started_at DESC").Limit(1).Select()
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+func activeExpandContractMigration(db orm.DB) (*expandContractMigration, error) {
+	exists, err := checkIfTableExists("pg_migrations", db)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	var m expandContractMigration
+	err = db.Model(&m).Where("active").Select()
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+func startExpandContract(db *pg.DB, name string) error {
+	err := ensureExpandContractTable(db)
+	if err != nil {
+		return err
+	}
+
+	reg, ok := expandContractMigrations[name]
+	if !ok {
+		return ErrNoSuchExpandContractMigration
+	}
+
+	active, err := activeExpandContractMigration(db)
+	if err != nil {
+		return err
+	}
+	if active != nil {
+		return ErrAlreadyActive
+	}
+
+	parent, err := lastExpandContractMigration(db)
+	if err != nil {
+		return err
+	}
+
+	run := func(db orm.DB) error {
+		if reg.phases.Expand != nil {
+			if err := reg.phases.Expand(db); err != nil {
+				return err
+			}
+		}
+		if reg.phases.Backfill != nil {
+			if err := reg.phases.Backfill(db); err != nil {
+				return err
+			}
+		}
+
+		m := expandContractMigration{
+			Name:      name,
+			Active:    true,
+			StartedAt: time.Now(),
+		}
+		if parent != nil {
+			m.ParentName = &parent.Name
+		}
+
+		_, err := db.Model(&m).Insert()
+		return err
+	}
+
+	if reg.disableTransaction {
+		err = run(db)
+	} else {
+		ctx := context.Background()
+		err = db.RunInTransaction(ctx, func(tx *pg.Tx) error {
+			return run(tx)
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %s", name, err)
+	}
+
+	fmt.Printf("Started %q; it is now active\n", name)
+	return nil
+}
+
+func completeExpandContract(db *pg.DB, name string) error {
+	m, err := activeExpandContractMigration(db)
+	if err != nil {
+		return err
+	}
+	if m == nil || m.Name != name {
+		return ErrNotActive
+	}
+
+	reg, ok := expandContractMigrations[name]
+	if !ok {
+		return ErrNoSuchExpandContractMigration
+	}
+
+	run := func(db orm.DB) error {
+		if reg.phases.Contract != nil {
+			if err := reg.phases.Contract(db); err != nil {
+				return err
+			}
+		}
+
+		m.Active = false
+		m.CompletedAt = time.Now()
+		_, err := db.Model(m).WherePK().Update()
+		return err
+	}
+
+	if reg.disableTransaction {
+		err = run(db)
+	} else {
+		ctx := context.Background()
+		err = db.RunInTransaction(ctx, func(tx *pg.Tx) error {
+			return run(tx)
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %s", name, err)
+	}
+
+	fmt.Printf("Completed %q\n", name)
+	return nil
+}
+
+func abortExpandContract(db *pg.DB, name string) error {
+	m, err := activeExpandContractMigration(db)
+	if err != nil {
+		return err
+	}
+	if m == nil || m.Name != name {
+		return ErrNotActive
+	}
+
+	reg, ok := expandContractMigrations[name]
+	if !ok {
+		return ErrNoSuchExpandContractMigration
+	}
+
+	run := func(db orm.DB) error {
+		if reg.phases.AbortExpand != nil {
+			if err := reg.phases.AbortExpand(db); err != nil {
+				return err
+			}
+		}
+
+		_, err := db.Model(m).WherePK().Delete()
+		return err
+	}
+
+	if reg.disableTransaction {
+		err = run(db)
+	} else {
+		ctx := context.Background()
+		err = db.RunInTransaction(ctx, func(tx *pg.Tx) error {
+			return run(tx)
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %s", name, err)
+	}
+
+	fmt.Printf("Aborted %q\n", name)
+	return nil
+}
+
+// LatestVersion returns the name of the most recently started expand/contract
+// migration, whether it's active or completed, so application code can tell
+// which version of the schema it should expect to find. It returns "" before
+// start has ever run against db, including on a database where pg_migrations
+// doesn't exist yet.
+func LatestVersion(db orm.DB) (string, error) {
+	m, err := lastExpandContractMigration(db)
+	if err != nil {
+		return "", err
+	}
+	if m == nil {
+		return "", nil
+	}
+	return m.Name, nil
+}
+
+// IsActiveMigrationPeriod reports whether an expand/contract migration is
+// currently active, meaning both the old and new schema may be in use and
+// application code should be prepared to read and write both. It reports
+// false before start has ever run against db, including on a database where
+// pg_migrations doesn't exist yet.
+func IsActiveMigrationPeriod(db orm.DB) (bool, error) {
+	m, err := activeExpandContractMigration(db)
+	if err != nil {
+		return false, err
+	}
+	return m != nil, nil
+}
@@ -0,0 +1,115 @@
+package migrations
+
+import (
+	"os"
+	"testing"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clearExpandContractMigrations(t *testing.T, db *pg.DB) {
+	t.Helper()
+
+	_, err := db.Exec("DROP TABLE IF EXISTS pg_migrations")
+	assert.Nil(t, err)
+
+	for name := range expandContractMigrations {
+		delete(expandContractMigrations, name)
+	}
+}
+
+func TestExpandContract(t *testing.T) {
+	db := pg.Connect(&pg.Options{
+		Addr:     "localhost:5432",
+		User:     os.Getenv("TEST_DATABASE_USER"),
+		Database: os.Getenv("TEST_DATABASE_NAME"),
+	})
+
+	defer clearExpandContractMigrations(t, db)
+
+	t.Run("LatestVersion and IsActiveMigrationPeriod degrade gracefully before start has ever run", func(tt *testing.T) {
+		clearExpandContractMigrations(tt, db)
+
+		version, err := LatestVersion(db)
+		assert.Nil(tt, err)
+		assert.Equal(tt, "", version)
+
+		active, err := IsActiveMigrationPeriod(db)
+		assert.Nil(tt, err)
+		assert.False(tt, active)
+	})
+
+	t.Run("start and complete drive a migration through its phases", func(tt *testing.T) {
+		clearExpandContractMigrations(tt, db)
+
+		var expanded, backfilled, contracted bool
+		RegisterExpandContract("123", ExpandContractPhases{
+			Expand:   func(db orm.DB) error { expanded = true; return nil },
+			Backfill: func(db orm.DB) error { backfilled = true; return nil },
+			Contract: func(db orm.DB) error { contracted = true; return nil },
+		}, MigrationOptions{})
+
+		err := startExpandContract(db, "123")
+		require.Nil(tt, err)
+		assert.True(tt, expanded)
+		assert.True(tt, backfilled)
+
+		active, err := IsActiveMigrationPeriod(db)
+		assert.Nil(tt, err)
+		assert.True(tt, active)
+
+		version, err := LatestVersion(db)
+		assert.Nil(tt, err)
+		assert.Equal(tt, "123", version)
+
+		err = startExpandContract(db, "123")
+		assert.Equal(tt, ErrAlreadyActive, err)
+
+		err = completeExpandContract(db, "123")
+		require.Nil(tt, err)
+		assert.True(tt, contracted)
+
+		active, err = IsActiveMigrationPeriod(db)
+		assert.Nil(tt, err)
+		assert.False(tt, active)
+
+		err = completeExpandContract(db, "123")
+		assert.Equal(tt, ErrNotActive, err)
+	})
+
+	t.Run("abort runs AbortExpand and removes the row", func(tt *testing.T) {
+		clearExpandContractMigrations(tt, db)
+
+		var aborted bool
+		RegisterExpandContract("456", ExpandContractPhases{
+			AbortExpand: func(db orm.DB) error { aborted = true; return nil },
+		}, MigrationOptions{})
+
+		err := startExpandContract(db, "456")
+		require.Nil(tt, err)
+
+		err = abortExpandContract(db, "456")
+		require.Nil(tt, err)
+		assert.True(tt, aborted)
+
+		active, err := IsActiveMigrationPeriod(db)
+		assert.Nil(tt, err)
+		assert.False(tt, active)
+	})
+
+	t.Run("only allows one root migration", func(tt *testing.T) {
+		clearExpandContractMigrations(tt, db)
+
+		err := ensureExpandContractTable(db)
+		require.Nil(tt, err)
+
+		_, err = db.Exec(`INSERT INTO pg_migrations (name, parent_name, active, started_at) VALUES (?, NULL, false, now())`, "root-1")
+		require.Nil(tt, err)
+
+		_, err = db.Exec(`INSERT INTO pg_migrations (name, parent_name, active, started_at) VALUES (?, NULL, false, now())`, "root-2")
+		assert.Error(tt, err)
+	})
+}
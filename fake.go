@@ -0,0 +1,158 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+)
+
+// MigrateFake records the given migrations (or, if none are given, every
+// registered migration that hasn't run yet) as completed without running
+// their Up function, using the default Migrator. It's meant for adopting
+// this tool against a database whose schema already matches later
+// migrations, or for repairing the migrations table after manual DDL.
+func MigrateFake(db *pg.DB, directory string, names ...string) error {
+	return defaultMigrator.MigrateFake(db, names...)
+}
+
+// RollbackFake deletes the given migrations (or, if none are given, every
+// migration in the last run batch) from the migrations table without
+// running their Down function, using the default Migrator.
+func RollbackFake(db *pg.DB, directory string, names ...string) error {
+	return defaultMigrator.RollbackFake(db, names...)
+}
+
+// MigrateFake records the given migrations (or, if none are given, every
+// registered migration that hasn't run yet) as completed without running
+// their Up function.
+func (m *Migrator) MigrateFake(db *pg.DB, names ...string) error {
+	err := ensureMigrationTables(db, m.lockID)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	all := append([]migration(nil), m.migrations...)
+	m.mu.Unlock()
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+
+	completed, err := getCompletedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	uncompleted := filterMigrations(all, completed, false)
+	uncompleted = filterMigrationsByName(uncompleted, names)
+
+	if len(uncompleted) == 0 {
+		fmt.Println("No migrations to fake")
+		return nil
+	}
+
+	err = m.acquireLock(context.Background(), db)
+	if err != nil {
+		return err
+	}
+	defer m.releaseLock(db)
+
+	batch, err := getLastBatchNumber(db)
+	if err != nil {
+		return err
+	}
+	batch = batch + 1
+
+	fmt.Printf("Faking batch %d with %d migration(s)...\n", batch, len(uncompleted))
+
+	for _, mig := range uncompleted {
+		mig.Batch = batch
+		mig.CompletedAt = time.Now()
+		_, err = db.Model(&mig).Insert()
+		if err != nil {
+			return fmt.Errorf("%s: %s", mig.Name, err)
+		}
+		fmt.Printf("Faked %q\n", mig.Name)
+	}
+
+	return nil
+}
+
+// RollbackFake deletes the given migrations (or, if none are given, every
+// migration in the last run batch) from the migrations table without
+// running their Down function.
+func (m *Migrator) RollbackFake(db *pg.DB, names ...string) error {
+	err := ensureMigrationTables(db, m.lockID)
+	if err != nil {
+		return err
+	}
+
+	completed, err := getCompletedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	err = m.acquireLock(context.Background(), db)
+	if err != nil {
+		return err
+	}
+	defer m.releaseLock(db)
+
+	var target []migration
+	if len(names) > 0 {
+		target = filterMigrationsByName(completed, names)
+	} else {
+		batch, err := getLastBatchNumber(db)
+		if err != nil {
+			return err
+		}
+		if batch == 0 {
+			fmt.Println("No migrations have been run yet")
+			return nil
+		}
+		target = getMigrationsForBatch(completed, batch)
+	}
+
+	if len(target) == 0 {
+		fmt.Println("No migrations to fake rollback")
+		return nil
+	}
+
+	fmt.Printf("Faking rollback of %d migration(s)...\n", len(target))
+
+	for _, mig := range target {
+		_, err = db.Model(&mig).Where("name = ?", mig.Name).Delete()
+		if err != nil {
+			return fmt.Errorf("%s: %s", mig.Name, err)
+		}
+		fmt.Printf("Faked rollback of %q\n", mig.Name)
+	}
+
+	return nil
+}
+
+// filterMigrationsByName returns the subset of all whose Name is in names. If
+// names is empty, all is returned unfiltered.
+func filterMigrationsByName(all []migration, names []string) []migration {
+	if len(names) == 0 {
+		return all
+	}
+
+	wanted := map[string]bool{}
+	for _, n := range names {
+		wanted[n] = true
+	}
+
+	var filtered []migration
+	for _, m := range all {
+		if wanted[m.Name] {
+			filtered = append(filtered, m)
+		}
+	}
+
+	return filtered
+}
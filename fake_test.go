@@ -0,0 +1,114 @@
+package migrations
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateFake(t *testing.T) {
+	db := pg.Connect(&pg.Options{
+		Addr:     "localhost:5432",
+		User:     os.Getenv("TEST_DATABASE_USER"),
+		Database: os.Getenv("TEST_DATABASE_NAME"),
+	})
+
+	err := ensureMigrationTables(db, lockID)
+	require.Nil(t, err)
+
+	defer clearMigrations(t, db)
+	defer resetMigrations(t)
+
+	t.Run("marks migrations as completed without running Up", func(tt *testing.T) {
+		clearMigrations(tt, db)
+		resetMigrations(tt)
+
+		ran := false
+		setMigrations(tt, []migration{
+			{Name: "123", Up: func(db orm.DB) error { ran = true; return nil }, Down: noopMigration},
+		})
+
+		err := defaultMigrator.MigrateFake(db, "123")
+		assert.Nil(tt, err)
+		assert.False(tt, ran)
+
+		count, err := db.Model(&migration{}).Count()
+		assert.Nil(tt, err)
+		assert.Equal(tt, 1, count)
+	})
+
+	t.Run("only fakes the named migrations", func(tt *testing.T) {
+		clearMigrations(tt, db)
+		resetMigrations(tt)
+		setMigrations(tt, []migration{
+			{Name: "123", Up: noopMigration, Down: noopMigration},
+			{Name: "456", Up: noopMigration, Down: noopMigration},
+		})
+
+		err := defaultMigrator.MigrateFake(db, "123")
+		assert.Nil(tt, err)
+
+		count, err := db.Model(&migration{}).Count()
+		assert.Nil(tt, err)
+		assert.Equal(tt, 1, count)
+	})
+}
+
+func TestRollbackFake(t *testing.T) {
+	db := pg.Connect(&pg.Options{
+		Addr:     "localhost:5432",
+		User:     os.Getenv("TEST_DATABASE_USER"),
+		Database: os.Getenv("TEST_DATABASE_NAME"),
+	})
+
+	err := ensureMigrationTables(db, lockID)
+	require.Nil(t, err)
+
+	defer clearMigrations(t, db)
+	defer resetMigrations(t)
+
+	t.Run("deletes the last batch without running Down", func(tt *testing.T) {
+		clearMigrations(tt, db)
+		resetMigrations(tt)
+
+		ranDown := false
+		setMigrations(tt, []migration{
+			{Name: "123", Up: noopMigration, Down: func(db orm.DB) error { ranDown = true; return nil }, Batch: 1, CompletedAt: time.Now()},
+		})
+
+		_, err := db.Model(&defaultMigrator.migrations).Insert()
+		assert.Nil(tt, err)
+
+		err = defaultMigrator.RollbackFake(db)
+		assert.Nil(tt, err)
+		assert.False(tt, ranDown)
+
+		count, err := db.Model(&migration{}).Count()
+		assert.Nil(tt, err)
+		assert.Equal(tt, 0, count)
+	})
+
+	t.Run("only fakes the named migrations", func(tt *testing.T) {
+		clearMigrations(tt, db)
+		resetMigrations(tt)
+		setMigrations(tt, []migration{
+			{Name: "123", Up: noopMigration, Down: noopMigration, Batch: 1, CompletedAt: time.Now()},
+			{Name: "456", Up: noopMigration, Down: noopMigration, Batch: 1, CompletedAt: time.Now()},
+		})
+
+		_, err := db.Model(&defaultMigrator.migrations).Insert()
+		assert.Nil(tt, err)
+
+		err = defaultMigrator.RollbackFake(db, "123")
+		assert.Nil(tt, err)
+
+		count, err := db.Model(&migration{}).Count()
+		assert.Nil(tt, err)
+		assert.Equal(tt, 1, count)
+	})
+}
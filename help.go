@@ -0,0 +1,34 @@
+package migrations
+
+import "fmt"
+
+const helpText = `Usage:
+  go run %s/*.go [command]
+
+Commands:
+  create   - create a new migration in %s with the provided name
+  migrate  - run any migrations that haven't been run yet
+  rollback - roll back the previous run batch of migrations
+  fake     - mark a migration as completed without running its Up function
+  start    - run the Expand phase of an expand/contract migration and mark it active
+  complete - run the Contract phase of an active expand/contract migration
+  abort    - reverse the Expand phase of an active expand/contract migration
+  status   - list registered migrations and whether they're applied (add --format=json for machine-readable output)
+  help     - print this help text
+
+Examples:
+  go run %s/*.go create create_users_table
+  go run %s/*.go migrate
+  go run %s/*.go rollback
+  go run %s/*.go fake 20060102150405_add_column
+  go run %s/*.go start 20060102150405_add_column
+  go run %s/*.go complete 20060102150405_add_column
+  go run %s/*.go abort 20060102150405_add_column
+  go run %s/*.go status
+  go run %s/*.go status --format=json
+  go run %s/*.go help
+`
+
+func help(directory string) {
+	fmt.Printf(helpText, directory, directory, directory, directory, directory, directory, directory, directory, directory, directory, directory, directory)
+}
@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+func resetMigrations(t *testing.T) {
+	t.Helper()
+	defaultMigrator.UnregisterAll()
+}
+
+func setMigrations(t *testing.T, ms []migration) {
+	t.Helper()
+	defaultMigrator.mu.Lock()
+	defer defaultMigrator.mu.Unlock()
+	defaultMigrator.migrations = ms
+}
+
+func clearMigrations(t *testing.T, db *pg.DB) {
+	t.Helper()
+
+	_, err := db.Exec("DELETE FROM migrations")
+	assert.Nil(t, err)
+	_, err = db.Exec("DROP TABLE IF EXISTS test_table")
+	assert.Nil(t, err)
+}
+
+func noopMigration(db orm.DB) error {
+	return nil
+}
+
+func erringMigration(db orm.DB) error {
+	_, err := db.Exec("CREATE TABLE test_table (id integer)")
+	if err != nil {
+		return err
+	}
+	return errors.New("error")
+}
+
+func assertTable(t *testing.T, db *pg.DB, table string, exists bool) {
+	t.Helper()
+
+	want := 0
+	msg := "expected %q table to not exist"
+	if exists {
+		want = 1
+		msg = "expected %q table to exist"
+	}
+
+	count, err := orm.NewQuery(db).
+		Table("information_schema.tables").
+		Where("table_name = ?", table).
+		Where("table_schema = current_schema").
+		Count()
+	assert.Nil(t, err)
+	assert.Equalf(t, want, count, msg, table)
+}
@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// InitSchema registers a function that installs a snapshot of the current
+// schema for the default Migrator. It only runs once, against a fresh
+// database (an empty migrations table): migrate runs it in place of every
+// registered migration's Up function, then marks each registered migration
+// as completed in the same transaction. This lets long-lived projects prune
+// migration files that a new environment will never need, without breaking
+// bootstrap.
+func InitSchema(fn func(orm.DB) error) {
+	defaultMigrator.InitSchema(fn)
+}
+
+// InitSchema registers a function that installs a snapshot of the current
+// schema for m; see the package-level InitSchema for details.
+func (m *Migrator) InitSchema(fn func(orm.DB) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.initSchema = fn
+}
+
+func (m *Migrator) runInitSchema(ctx context.Context, db *pg.DB, all []migration, initSchema func(orm.DB) error, reporter Reporter) error {
+	err := m.acquireLock(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer m.releaseLock(db)
+
+	fmt.Printf("Initializing schema and marking %d migration(s) as completed...\n", len(all))
+
+	err = db.RunInTransaction(ctx, func(tx *pg.Tx) error {
+		if err := initSchema(tx); err != nil {
+			return err
+		}
+
+		for _, mig := range all {
+			mig.Batch = 1
+			mig.CompletedAt = time.Now()
+
+			reporter.OnStart(ActionInitSchema, mig.Name)
+			reporter.OnFinish(ActionInitSchema, mig.Name, 0, nil)
+
+			if _, err := tx.Model(&mig).Insert(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("init schema: %s", err)
+	}
+
+	fmt.Println("Finished initializing schema")
+	return nil
+}
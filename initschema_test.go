@@ -0,0 +1,84 @@
+package migrations
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitSchema(t *testing.T) {
+	ctx := context.Background()
+	db := pg.Connect(&pg.Options{
+		Addr:     "localhost:5432",
+		User:     os.Getenv("TEST_DATABASE_USER"),
+		Database: os.Getenv("TEST_DATABASE_NAME"),
+	})
+
+	err := ensureMigrationTables(db, lockID)
+	require.Nil(t, err)
+
+	defer clearMigrations(t, db)
+	defer resetMigrations(t)
+	defer defaultMigrator.InitSchema(nil)
+
+	t.Run("runs init schema instead of Up on a fresh database", func(tt *testing.T) {
+		clearMigrations(tt, db)
+		resetMigrations(tt)
+
+		ran := false
+		setMigrations(tt, []migration{
+			{Name: "123", Up: func(db orm.DB) error { ran = true; return nil }, Down: noopMigration},
+			{Name: "456", Up: func(db orm.DB) error { ran = true; return nil }, Down: noopMigration},
+		})
+
+		initialized := false
+		defaultMigrator.InitSchema(func(db orm.DB) error {
+			initialized = true
+			return nil
+		})
+
+		spy := &spyReporter{}
+		err := defaultMigrator.migrate(ctx, db, spy)
+		assert.Nil(tt, err)
+
+		assert.True(tt, initialized)
+		assert.False(tt, ran)
+		assert.Equal(tt, []string{"123", "456"}, spy.started)
+
+		count, err := db.Model(&migration{}).Count()
+		assert.Nil(tt, err)
+		assert.Equal(tt, 2, count)
+	})
+
+	t.Run("doesn't run init schema again once migrations have completed", func(tt *testing.T) {
+		clearMigrations(tt, db)
+		resetMigrations(tt)
+		setMigrations(tt, []migration{
+			{Name: "123", Up: noopMigration, Down: noopMigration, Batch: 1, CompletedAt: time.Now()},
+		})
+
+		_, err := db.Model(&defaultMigrator.migrations).Insert()
+		assert.Nil(tt, err)
+
+		setMigrations(tt, []migration{
+			{Name: "123", Up: noopMigration, Down: noopMigration},
+			{Name: "456", Up: noopMigration, Down: noopMigration},
+		})
+
+		initCalled := false
+		defaultMigrator.InitSchema(func(db orm.DB) error {
+			initCalled = true
+			return nil
+		})
+
+		err = defaultMigrator.migrate(ctx, db, defaultReporter{})
+		assert.Nil(tt, err)
+		assert.False(tt, initCalled)
+	})
+}
@@ -10,19 +10,25 @@ import (
 	"github.com/go-pg/pg/v10/orm"
 )
 
-var migrations []migration
-
-// UnregisterAll clears all registered migrations. This is udeful when you need
-// to run migrations on multiple databases, and due to thread-unsafe nature of this package,
-// this function must be called between each migrations.Run()
+// UnregisterAll clears all migrations registered on the default Migrator.
+// This is useful when you need to run migrations on multiple databases: call
+// it between each migrations.Run() so the previous database's migrations
+// don't leak into the next one, or use your own Migrator instead.
 func UnregisterAll() {
-	migrations = []migration{}
+	defaultMigrator.UnregisterAll()
 }
 
-// Register accepts a name, up, down, and options and adds the migration to the
-// global migrations slice.
+// Register accepts a name, up, down, and options and adds the migration to
+// the default Migrator's migration list.
 func Register(name string, up, down func(orm.DB) error, opts MigrationOptions) {
-	migrations = append(migrations, migration{
+	defaultMigrator.Register(name, up, down, opts)
+}
+
+// Register adds a migration to m's migration list.
+func (m *Migrator) Register(name string, up, down func(orm.DB) error, opts MigrationOptions) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.migrations = append(m.migrations, migration{
 		Name:               name,
 		Up:                 up,
 		Down:               down,
@@ -30,11 +36,28 @@ func Register(name string, up, down func(orm.DB) error, opts MigrationOptions) {
 	})
 }
 
-func migrate(db *pg.DB, directory string) error {
+// UnregisterAll clears m's registered migrations.
+func (m *Migrator) UnregisterAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.migrations = nil
+}
+
+func (m *Migrator) migrate(ctx context.Context, db *pg.DB, reporter Reporter) error {
+	err := ensureMigrationTables(db, m.lockID)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	all := append([]migration(nil), m.migrations...)
+	initSchema := m.initSchema
+	m.mu.Unlock()
+
 	// sort the registered migrations by name (which will sort by the
 	// timestamp in their names)
-	sort.Slice(migrations, func(i, j int) bool {
-		return migrations[i].Name < migrations[j].Name
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
 	})
 
 	// look at the migrations table to see the already run migrations
@@ -43,9 +66,15 @@ func migrate(db *pg.DB, directory string) error {
 		return err
 	}
 
+	// on a fresh database, run the registered init schema instead of every
+	// migration's Up function, and mark them all completed in one batch
+	if len(completed) == 0 && initSchema != nil {
+		return m.runInitSchema(ctx, db, all, initSchema, reporter)
+	}
+
 	// diff the completed migrations from the registered migrations to find
 	// the migrations we still need to run
-	uncompleted := filterMigrations(migrations, completed, false)
+	uncompleted := filterMigrations(all, completed, false)
 
 	// if there are no migrations that need to be run, exit early
 	if len(uncompleted) == 0 {
@@ -54,11 +83,11 @@ func migrate(db *pg.DB, directory string) error {
 	}
 
 	// acquire the migration lock from the migrations_lock table
-	err = acquireLock(db)
+	err = m.acquireLock(ctx, db)
 	if err != nil {
 		return err
 	}
-	defer releaseLock(db)
+	defer m.releaseLock(db)
 
 	// find the last batch number
 	batch, err := getLastBatchNumber(db)
@@ -69,27 +98,31 @@ func migrate(db *pg.DB, directory string) error {
 
 	fmt.Printf("Running batch %d with %d migration(s)...\n", batch, len(uncompleted))
 
-	for _, m := range uncompleted {
-		m.Batch = batch
+	for _, mig := range uncompleted {
+		mig.Batch = batch
+		reporter.OnStart(ActionMigrate, mig.Name)
+
+		started := time.Now()
 		var err error
-		if m.DisableTransaction {
-			err = m.Up(db)
+		if mig.DisableTransaction {
+			err = mig.Up(db)
 		} else {
-			ctx := context.Background()
 			err = db.RunInTransaction(ctx, func(tx *pg.Tx) error {
-				return m.Up(tx)
+				return mig.Up(tx)
 			})
 		}
+		dur := time.Since(started)
+		reporter.OnFinish(ActionMigrate, mig.Name, dur, err)
 		if err != nil {
-			return fmt.Errorf("%s: %s", m.Name, err)
+			return fmt.Errorf("%s: %s", mig.Name, err)
 		}
 
-		m.CompletedAt = time.Now()
-		_, err = db.Model(&m).Insert()
+		mig.CompletedAt = time.Now()
+		mig.DurationMs = dur.Milliseconds()
+		_, err = db.Model(&mig).Insert()
 		if err != nil {
-			return fmt.Errorf("%s: %s", m.Name, err)
+			return fmt.Errorf("%s: %s", mig.Name, err)
 		}
-		fmt.Printf("Finished running %q\n", m.Name)
 	}
 
 	return nil
@@ -127,10 +160,9 @@ func filterMigrations(all, subset []migration, wantCompleted bool) []migration {
 	return d
 }
 
-func acquireLock(db *pg.DB) error {
-	ctx := context.Background()
+func (m *Migrator) acquireLock(ctx context.Context, db *pg.DB) error {
 	return db.RunInTransaction(ctx, func(tx *pg.Tx) error {
-		l := lock{ID: lockID}
+		l := lock{ID: m.lockID}
 
 		err := tx.Model(&l).
 			For("UPDATE").
@@ -149,8 +181,8 @@ func acquireLock(db *pg.DB) error {
 	})
 }
 
-func releaseLock(db orm.DB) error {
-	l := lock{ID: lockID, IsLocked: false}
+func (m *Migrator) releaseLock(db orm.DB) error {
+	l := lock{ID: m.lockID, IsLocked: false}
 	_, err := db.Model(&l).Update()
 	return err
 }
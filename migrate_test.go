@@ -0,0 +1,93 @@
+package migrations
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	db := pg.Connect(&pg.Options{
+		Addr:     "localhost:5432",
+		User:     os.Getenv("TEST_DATABASE_USER"),
+		Database: os.Getenv("TEST_DATABASE_NAME"),
+	})
+
+	err := ensureMigrationTables(db, lockID)
+	require.Nil(t, err)
+
+	defer clearMigrations(t, db)
+	defer resetMigrations(t)
+
+	t.Run("runs uncompleted migrations in order and reports progress", func(tt *testing.T) {
+		clearMigrations(tt, db)
+		resetMigrations(tt)
+		setMigrations(tt, []migration{
+			{Name: "123", Up: noopMigration, Down: noopMigration},
+			{Name: "456", Up: noopMigration, Down: noopMigration},
+		})
+
+		spy := &spyReporter{}
+		err := defaultMigrator.migrate(ctx, db, spy)
+		assert.Nil(tt, err)
+
+		assert.Equal(tt, []string{"123", "456"}, spy.started)
+
+		count, err := db.Model(&migration{}).Count()
+		assert.Nil(tt, err)
+		assert.Equal(tt, 2, count)
+	})
+
+	t.Run("records how long each migration took", func(tt *testing.T) {
+		clearMigrations(tt, db)
+		resetMigrations(tt)
+		setMigrations(tt, []migration{
+			{Name: "123", Up: noopMigration, Down: noopMigration},
+		})
+
+		err := defaultMigrator.migrate(ctx, db, defaultReporter{})
+		assert.Nil(tt, err)
+
+		var m migration
+		err = db.Model(&m).Where("name = ?", "123").Select()
+		assert.Nil(tt, err)
+		assert.GreaterOrEqual(tt, m.DurationMs, int64(0))
+	})
+
+	t.Run("exits early if migrations are already up to date", func(tt *testing.T) {
+		clearMigrations(tt, db)
+		resetMigrations(tt)
+		setMigrations(tt, []migration{
+			{Name: "123", Up: noopMigration, Down: noopMigration, Batch: 1, CompletedAt: time.Now()},
+		})
+
+		_, err := db.Model(&defaultMigrator.migrations).Insert()
+		assert.Nil(tt, err)
+
+		spy := &spyReporter{}
+		err = defaultMigrator.migrate(ctx, db, spy)
+		assert.Nil(tt, err)
+		assert.Empty(tt, spy.started)
+	})
+
+	t.Run("returns an error if the migration lock is already held", func(tt *testing.T) {
+		clearMigrations(tt, db)
+		resetMigrations(tt)
+		setMigrations(tt, []migration{
+			{Name: "123", Up: noopMigration, Down: noopMigration},
+		})
+
+		err := defaultMigrator.acquireLock(ctx, db)
+		assert.Nil(tt, err)
+		defer defaultMigrator.releaseLock(db)
+
+		err = defaultMigrator.migrate(ctx, db, defaultReporter{})
+		assert.Equal(tt, ErrAlreadyLocked, err)
+	})
+}
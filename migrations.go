@@ -0,0 +1,81 @@
+// Package migrations provides a robust mechanism for registering, creating, and
+// running migrations using go-pg.
+package migrations
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/go-pg/pg/v10"
+)
+
+// Errors that can be returned from Run.
+var (
+	ErrCreateRequiresName = errors.New("migration name is required for create")
+	ErrNameRequired       = errors.New("migration name is required")
+)
+
+// Run takes in a directory and an argument slice and runs the appropriate command.
+func Run(db *pg.DB, directory string, args []string) error {
+	return RunWithOptions(db, directory, args, RunOptions{})
+}
+
+// RunWithOptions behaves like Run, but additionally accepts a RunOptions for
+// configuring things like progress reporting.
+func RunWithOptions(db *pg.DB, directory string, args []string, opts RunOptions) error {
+	reporter := opts.Reporter
+	if reporter == nil {
+		reporter = defaultReporter{}
+	}
+
+	ctx := context.Background()
+
+	cmd := ""
+
+	if len(args) > 1 {
+		cmd = args[1]
+	}
+
+	switch cmd {
+	case "migrate":
+		return defaultMigrator.migrate(ctx, db, reporter)
+	case "create":
+		if len(args) < 3 {
+			return ErrCreateRequiresName
+		}
+		name := args[2]
+		return create(directory, name)
+	case "rollback":
+		if len(args) > 2 {
+			names := strings.Split(strings.ReplaceAll(args[2], " ", ""), ",")
+			return defaultMigrator.rollbackNamed(ctx, db, names, reporter)
+		}
+		return defaultMigrator.rollback(ctx, db, reporter)
+	case "fake":
+		if len(args) < 3 {
+			return ErrNameRequired
+		}
+		return MigrateFake(db, directory, args[2])
+	case "start":
+		if len(args) < 3 {
+			return ErrNameRequired
+		}
+		return startExpandContract(db, args[2])
+	case "complete":
+		if len(args) < 3 {
+			return ErrNameRequired
+		}
+		return completeExpandContract(db, args[2])
+	case "abort":
+		if len(args) < 3 {
+			return ErrNameRequired
+		}
+		return abortExpandContract(db, args[2])
+	case "status":
+		return statusCmd(db, args[2:])
+	default:
+		help(directory)
+		return nil
+	}
+}
@@ -0,0 +1,162 @@
+package migrations
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// MigratorOptions configures a Migrator.
+type MigratorOptions struct {
+	// LockID identifies this Migrator's row in the migration_lock table.
+	// Migrators that share a LockID serialize against each other; give each
+	// Migrator its own LockID if they should run independently against the
+	// same database. Defaults to "lock".
+	LockID string
+
+	// Reporter receives progress events for migrations run through Migrate,
+	// Rollback, and RollbackNamed. Defaults to a reporter that prints to
+	// stdout.
+	Reporter Reporter
+}
+
+// Migrator owns a registered list of migrations, independent of any other
+// Migrator in the process. The package-level Register/Run functions operate
+// against a shared default Migrator, which isn't safe to reuse across more
+// than one database at a time (see the older UnregisterAll workaround); code
+// that needs to run migrations against several databases concurrently -
+// tests, multi-tenant workers - should construct its own Migrator per
+// database instead.
+//
+// A Migrator always tracks state in the migrations and migration_lock
+// tables; those table names aren't configurable, only the LockID is.
+// Migrators sharing a database must use distinct LockIDs to run
+// independently of each other, but they still share the one migrations
+// table: Status on one Migrator will report the other's rows as Drift
+// rather than run or rollback them.
+type Migrator struct {
+	mu         sync.Mutex
+	migrations []migration
+	initSchema func(orm.DB) error
+
+	lockID   string
+	reporter Reporter
+}
+
+// NewMigrator creates a Migrator with its own migration list and lock.
+func NewMigrator(opts MigratorOptions) *Migrator {
+	id := opts.LockID
+	if id == "" {
+		id = lockID
+	}
+
+	reporter := opts.Reporter
+	if reporter == nil {
+		reporter = defaultReporter{}
+	}
+
+	return &Migrator{
+		lockID:   id,
+		reporter: reporter,
+	}
+}
+
+// defaultMigrator backs the package-level Register/Run/... functions, kept
+// around for callers that don't need more than one Migrator in a process.
+var defaultMigrator = NewMigrator(MigratorOptions{})
+
+// Migrate runs any of m's registered migrations that haven't been run yet.
+func (m *Migrator) Migrate(ctx context.Context, db *pg.DB) error {
+	return m.migrate(ctx, db, m.reporter)
+}
+
+// Rollback rolls back the previous batch of migrations that m ran.
+func (m *Migrator) Rollback(ctx context.Context, db *pg.DB) error {
+	return m.rollback(ctx, db, m.reporter)
+}
+
+// RollbackNamed rolls back the named migrations, regardless of what batch
+// they were run in.
+func (m *Migrator) RollbackNamed(ctx context.Context, db *pg.DB, names []string) error {
+	return m.rollbackNamed(ctx, db, names, m.reporter)
+}
+
+// MigrationStatus reports whether a single migration has been applied to a
+// database. Drift is set when the row came from the migrations table but
+// has no corresponding registration on the Migrator that produced this
+// status - something else applied it, or it was registered by a process
+// that has since removed it.
+type MigrationStatus struct {
+	Name        string     `json:"name"`
+	Applied     bool       `json:"applied"`
+	Batch       int32      `json:"batch,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	DurationMs  int64      `json:"durationMs,omitempty"`
+	Drift       bool       `json:"drift,omitempty"`
+}
+
+// Status reports the applied/pending state of every migration m has
+// registered, ordered the same way Migrate would run them, followed by a
+// drift entry for every completed migration in the database that isn't
+// registered on m.
+func (m *Migrator) Status(ctx context.Context, db *pg.DB) ([]MigrationStatus, error) {
+	err := ensureMigrationTables(db, m.lockID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	all := append([]migration(nil), m.migrations...)
+	m.mu.Unlock()
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+
+	completed, err := getCompletedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	completedByName := make(map[string]migration, len(completed))
+	for _, c := range completed {
+		completedByName[c.Name] = c
+	}
+
+	registered := make(map[string]bool, len(all))
+	statuses := make([]MigrationStatus, len(all))
+	for i, mig := range all {
+		registered[mig.Name] = true
+
+		s := MigrationStatus{Name: mig.Name}
+		if c, ok := completedByName[mig.Name]; ok {
+			completedAt := c.CompletedAt
+			s.Applied = true
+			s.Batch = c.Batch
+			s.CompletedAt = &completedAt
+			s.DurationMs = c.DurationMs
+		}
+		statuses[i] = s
+	}
+
+	for _, c := range completed {
+		if registered[c.Name] {
+			continue
+		}
+		completedAt := c.CompletedAt
+		statuses = append(statuses, MigrationStatus{
+			Name:        c.Name,
+			Applied:     true,
+			Batch:       c.Batch,
+			CompletedAt: &completedAt,
+			DurationMs:  c.DurationMs,
+			Drift:       true,
+		})
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,70 @@
+package migrations
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMigratorIsolation(t *testing.T) {
+	ctx := context.Background()
+	db := pg.Connect(&pg.Options{
+		Addr:     "localhost:5432",
+		User:     os.Getenv("TEST_DATABASE_USER"),
+		Database: os.Getenv("TEST_DATABASE_NAME"),
+	})
+
+	defer clearMigrations(t, db)
+
+	clearMigrations(t, db)
+
+	a := NewMigrator(MigratorOptions{LockID: "a"})
+	b := NewMigrator(MigratorOptions{LockID: "b"})
+
+	a.Register("123", noopMigration, noopMigration, MigrationOptions{})
+	b.Register("456", noopMigration, noopMigration, MigrationOptions{})
+
+	err := a.Migrate(ctx, db)
+	require.Nil(t, err)
+
+	err = b.Migrate(ctx, db)
+	require.Nil(t, err)
+
+	assert.Len(t, a.migrations, 1)
+	assert.Len(t, b.migrations, 1)
+
+	aStatuses, err := a.Status(ctx, db)
+	require.Nil(t, err)
+
+	bStatuses, err := b.Status(ctx, db)
+	require.Nil(t, err)
+
+	// a and b share the one migrations table, so each sees the other's row
+	// as drift rather than it leaking into its own registered list.
+	var aDrift, bDrift int
+	for _, s := range aStatuses {
+		if s.Drift {
+			aDrift++
+		}
+	}
+	for _, s := range bStatuses {
+		if s.Drift {
+			bDrift++
+		}
+	}
+	assert.Equal(t, 1, aDrift)
+	assert.Equal(t, 1, bDrift)
+
+	// a and b use distinct LockIDs, so a holding its lock doesn't block b.
+	err = a.acquireLock(ctx, db)
+	require.Nil(t, err)
+	defer a.releaseLock(db)
+
+	b.Register("789", noopMigration, noopMigration, MigrationOptions{})
+	err = b.Migrate(ctx, db)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// ErrAlreadyLocked is returned when migrate or rollback can't acquire the
+// migration lock because another process is already holding it.
+var ErrAlreadyLocked = errors.New("migration table is already locked")
+
+// MigrationOptions allows settings to be configured on a per-migration basis.
+type MigrationOptions struct {
+	DisableTransaction bool
+}
+
+type migration struct {
+	tableName struct{} `sql:"migrations,alias:migrations"`
+
+	ID          int32
+	Name        string
+	Batch       int32
+	CompletedAt time.Time
+	DurationMs  int64              `pg:",use_zero"`
+	Up          func(orm.DB) error `sql:"-"`
+	Down        func(orm.DB) error `sql:"-"`
+
+	DisableTransaction bool `sql:"-"`
+}
+
+type lock struct {
+	tableName struct{} `sql:"migration_lock,alias:migration_lock"`
+
+	ID       string
+	IsLocked bool `pg:",use_zero" sql:",notnull"`
+}
+
+const lockID = "lock"
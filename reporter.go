@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"fmt"
+	"time"
+)
+
+// Action identifies which operation a Reporter event belongs to, so a single
+// Reporter can tell a migrate from a rollback.
+type Action string
+
+// Actions reported by the package's migrate, rollback, and init schema
+// operations.
+const (
+	ActionMigrate    Action = "migrate"
+	ActionRollback   Action = "rollback"
+	ActionInitSchema Action = "init_schema"
+)
+
+// Reporter receives progress events for each migration or rollback that
+// runs, so callers can plug in their own logging or metrics in place of the
+// package's default stdout output.
+type Reporter interface {
+	OnStart(action Action, name string)
+	OnFinish(action Action, name string, dur time.Duration, err error)
+}
+
+// RunOptions configures the behavior of RunWithOptions.
+type RunOptions struct {
+	// Reporter receives progress events for each migration that runs. If
+	// nil, a default reporter that prints to stdout is used.
+	Reporter Reporter
+}
+
+type defaultReporter struct{}
+
+func (defaultReporter) OnStart(action Action, name string) {}
+
+func (defaultReporter) OnFinish(action Action, name string, dur time.Duration, err error) {
+	if err != nil {
+		return
+	}
+	switch action {
+	case ActionRollback:
+		fmt.Printf("Finished rolling back %q (%s)\n", name, dur)
+	case ActionInitSchema:
+		fmt.Printf("Marked %q as completed via init schema\n", name)
+	default:
+		fmt.Printf("Finished running %q (%s)\n", name, dur)
+	}
+}
@@ -1,18 +1,28 @@
 package migrations
 
 import (
+	"context"
 	"fmt"
 	"sort"
-	"strings"
+	"time"
 
-	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/v10"
 )
 
-func rollback(db *pg.DB, directory string) error {
+func (m *Migrator) rollback(ctx context.Context, db *pg.DB, reporter Reporter) error {
+	err := ensureMigrationTables(db, m.lockID)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	all := append([]migration(nil), m.migrations...)
+	m.mu.Unlock()
+
 	// sort the registered migrations by name (which will sort by the
 	// timestamp in their names)
-	sort.Slice(migrations, func(i, j int) bool {
-		return migrations[i].Name > migrations[j].Name
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name > all[j].Name
 	})
 
 	// look at the migrations table to see the already run migrations
@@ -22,11 +32,11 @@ func rollback(db *pg.DB, directory string) error {
 	}
 
 	// acquire the migration lock from the migrations_lock table
-	err = acquireLock(db)
+	err = m.acquireLock(ctx, db)
 	if err != nil {
 		return err
 	}
-	defer releaseLock(db)
+	defer m.releaseLock(db)
 
 	batch, err := getLastBatchNumber(db)
 	if err != nil {
@@ -38,39 +48,51 @@ func rollback(db *pg.DB, directory string) error {
 		return nil
 	}
 
-	rollback := getMigrationsForBatch(completed, batch)
-	rollback = filterMigrations(migrations, rollback, true)
+	toRollback := getMigrationsForBatch(completed, batch)
+	toRollback = filterMigrations(all, toRollback, true)
+
+	fmt.Printf("Rolling back batch %d with %d migration(s)...\n", batch, len(toRollback))
 
-	fmt.Printf("Rolling back batch %d with %d migration(s)...\n", batch, len(rollback))
+	for _, mig := range toRollback {
+		reporter.OnStart(ActionRollback, mig.Name)
 
-	for _, m := range rollback {
+		started := time.Now()
 		var err error
-		if m.DisableTransaction {
-			err = m.Down(db)
+		if mig.DisableTransaction {
+			err = mig.Down(db)
 		} else {
-			err = db.RunInTransaction(func(tx *pg.Tx) error {
-				return m.Down(tx)
+			err = db.RunInTransaction(ctx, func(tx *pg.Tx) error {
+				return mig.Down(tx)
 			})
 		}
+		reporter.OnFinish(ActionRollback, mig.Name, time.Since(started), err)
 		if err != nil {
-			return fmt.Errorf("%s: %s", m.Name, err)
+			return fmt.Errorf("%s: %s", mig.Name, err)
 		}
 
-		_, err = db.Model(&m).Where("name = ?", m.Name).Delete()
+		_, err = db.Model(&mig).Where("name = ?", mig.Name).Delete()
 		if err != nil {
-			return fmt.Errorf("%s: %s", m.Name, err)
+			return fmt.Errorf("%s: %s", mig.Name, err)
 		}
-		fmt.Printf("Finished rolling back %q\n", m.Name)
 	}
 
 	return nil
 }
 
-func rollbackNamed(db *pg.DB, directory string, _mNamesToRollback string) error {
+func (m *Migrator) rollbackNamed(ctx context.Context, db *pg.DB, names []string, reporter Reporter) error {
+	err := ensureMigrationTables(db, m.lockID)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	all := append([]migration(nil), m.migrations...)
+	m.mu.Unlock()
+
 	// sort the registered migrations by name (which will sort by the
 	// timestamp in their names)
-	sort.Slice(migrations, func(i, j int) bool {
-		return migrations[i].Name > migrations[j].Name
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name > all[j].Name
 	})
 
 	// look at the migrations table to see the already run migrations
@@ -78,15 +100,13 @@ func rollbackNamed(db *pg.DB, directory string, _mNamesToRollback string) error
 	if err != nil {
 		return err
 	}
-	_mNamesToRollback = strings.Replace(_mNamesToRollback, " ", "", -1)
-	var mNamesToRollback []string = strings.Split(_mNamesToRollback, ",")
 
 	// acquire the migration lock from the migrations_lock table
-	err = acquireLock(db)
+	err = m.acquireLock(ctx, db)
 	if err != nil {
 		return err
 	}
-	defer releaseLock(db)
+	defer m.releaseLock(db)
 
 	batch, err := getLastBatchNumber(db)
 	if err != nil {
@@ -98,46 +118,38 @@ func rollbackNamed(db *pg.DB, directory string, _mNamesToRollback string) error
 		return nil
 	}
 
-	var rollback []migration = []migration{}
-
-	for _, mRecord := range completed {
+	toRollback := filterMigrationsByName(completed, names)
 
-		for _, name := range mNamesToRollback {
-			if mRecord.Name == name {
-				rollback = append(rollback, mRecord)
-			}
-		}
+	if len(toRollback) == 0 {
+		fmt.Println("No such named migrations exist!")
+		return nil
 	}
 
-	if len(rollback) > 0 {
-
-		rollback = filterMigrations(migrations, rollback, true)
-
-		fmt.Printf("Rolling back " + fmt.Sprint(len(rollback)) + " selected migration(s)...\n")
-		for _, m := range rollback {
-			var err error
-			if m.DisableTransaction {
-				err = m.Down(db)
-			} else {
-				err = db.RunInTransaction(func(tx *pg.Tx) error {
-					return m.Down(tx)
-				})
-			}
-			if err != nil {
-				return fmt.Errorf("%s: %s", m.Name, err)
-			}
-
-			_, err = db.Model(&m).Where("name = ?", m.Name).Delete()
-			if err != nil {
-				return fmt.Errorf("%s: %s", m.Name, err)
-			}
-			fmt.Printf("Finished rolling back %q\n", m.Name)
-		}
+	toRollback = filterMigrations(all, toRollback, true)
 
-	} else {
+	fmt.Printf("Rolling back %d selected migration(s)...\n", len(toRollback))
 
-		fmt.Print("No such named migrations exist! \n")
+	for _, mig := range toRollback {
+		reporter.OnStart(ActionRollback, mig.Name)
+
+		started := time.Now()
+		var err error
+		if mig.DisableTransaction {
+			err = mig.Down(db)
+		} else {
+			err = db.RunInTransaction(ctx, func(tx *pg.Tx) error {
+				return mig.Down(tx)
+			})
+		}
+		reporter.OnFinish(ActionRollback, mig.Name, time.Since(started), err)
+		if err != nil {
+			return fmt.Errorf("%s: %s", mig.Name, err)
+		}
 
+		_, err = db.Model(&mig).Where("name = ?", mig.Name).Delete()
+		if err != nil {
+			return fmt.Errorf("%s: %s", mig.Name, err)
+		}
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package migrations
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -11,15 +12,23 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+type spyReporter struct {
+	started []string
+}
+
+func (s *spyReporter) OnStart(action Action, name string) { s.started = append(s.started, name) }
+
+func (s *spyReporter) OnFinish(action Action, name string, dur time.Duration, err error) {}
+
 func TestRollback(t *testing.T) {
-	tmp := os.TempDir()
+	ctx := context.Background()
 	db := pg.Connect(&pg.Options{
 		Addr:     "localhost:5432",
 		User:     os.Getenv("TEST_DATABASE_USER"),
 		Database: os.Getenv("TEST_DATABASE_NAME"),
 	})
 
-	err := ensureMigrationTables(db)
+	err := ensureMigrationTables(db, lockID)
 	require.Nil(t, err)
 
 	defer clearMigrations(t, db)
@@ -28,43 +37,46 @@ func TestRollback(t *testing.T) {
 	t.Run("sorts migrations in reverse order", func(tt *testing.T) {
 		clearMigrations(tt, db)
 		resetMigrations(tt)
-		migrations = []migration{
-			{Name: "123", Up: noopMigration, Down: noopMigration},
-			{Name: "456", Up: noopMigration, Down: noopMigration},
-		}
+		setMigrations(tt, []migration{
+			{Name: "123", Up: noopMigration, Down: noopMigration, Batch: 1, CompletedAt: time.Now()},
+			{Name: "456", Up: noopMigration, Down: noopMigration, Batch: 1, CompletedAt: time.Now()},
+		})
+
+		_, err := db.Model(&defaultMigrator.migrations).Insert()
+		assert.Nil(tt, err)
 
-		err := rollback(db, tmp)
+		spy := &spyReporter{}
+		err = defaultMigrator.rollback(ctx, db, spy)
 		assert.Nil(tt, err)
 
-		assert.Equal(tt, "456", migrations[0].Name)
-		assert.Equal(tt, "123", migrations[1].Name)
+		assert.Equal(tt, []string{"456", "123"}, spy.started)
 	})
 
 	t.Run("returns an error if the migration lock is already held", func(tt *testing.T) {
 		clearMigrations(tt, db)
 		resetMigrations(tt)
-		migrations = []migration{
+		setMigrations(tt, []migration{
 			{Name: "123", Up: noopMigration, Down: noopMigration},
 			{Name: "456", Up: noopMigration, Down: noopMigration},
-		}
+		})
 
-		err := acquireLock(db)
+		err := defaultMigrator.acquireLock(ctx, db)
 		assert.Nil(tt, err)
-		defer releaseLock(db)
+		defer defaultMigrator.releaseLock(db)
 
-		err = rollback(db, tmp)
+		err = defaultMigrator.rollback(ctx, db, defaultReporter{})
 		assert.Equal(tt, ErrAlreadyLocked, err)
 	})
 
 	t.Run("exits early if there aren't any migrations to rollback", func(tt *testing.T) {
 		clearMigrations(tt, db)
 		resetMigrations(tt)
-		migrations = []migration{
+		setMigrations(tt, []migration{
 			{Name: "123", Up: noopMigration, Down: noopMigration},
 			{Name: "456", Up: noopMigration, Down: noopMigration},
-		}
+		})
 
-		err := rollback(db, tmp)
+		err := defaultMigrator.rollback(ctx, db, defaultReporter{})
 		assert.Nil(tt, err)
 
 		count, err := db.Model(&migration{}).Count()
@@ -75,18 +87,18 @@ func TestRollback(t *testing.T) {
 	t.Run("only rolls back the last batch", func(tt *testing.T) {
 		clearMigrations(tt, db)
 		resetMigrations(tt)
-		migrations = []migration{
+		setMigrations(tt, []migration{
 			{Name: "123", Up: noopMigration, Down: noopMigration, Batch: 4, CompletedAt: time.Now()},
 			{Name: "456", Up: noopMigration, Down: noopMigration, Batch: 5, CompletedAt: time.Now()},
 			{Name: "789", Up: noopMigration, Down: noopMigration, Batch: 5, CompletedAt: time.Now()},
 			{Name: "010", Up: noopMigration, Down: noopMigration},
-		}
+		})
 
-		m := migrations[:2]
+		m := defaultMigrator.migrations[:2]
 		_, err := db.Model(&m).Insert()
 		assert.Nil(tt, err)
 
-		err = rollback(db, tmp)
+		err = defaultMigrator.rollback(ctx, db, defaultReporter{})
 		assert.Nil(tt, err)
 
 		batch, err := getLastBatchNumber(db)
@@ -101,18 +113,18 @@ func TestRollback(t *testing.T) {
 	t.Run("only rolls back selected names", func(tt *testing.T) {
 		clearMigrations(tt, db)
 		resetMigrations(tt)
-		migrations = []migration{
+		setMigrations(tt, []migration{
 			{Name: "123", Up: noopMigration, Down: noopMigration, Batch: 4, CompletedAt: time.Now()},
 			{Name: "456", Up: noopMigration, Down: noopMigration, Batch: 5, CompletedAt: time.Now()},
 			{Name: "789", Up: noopMigration, Down: noopMigration, Batch: 5, CompletedAt: time.Now()},
 			{Name: "010", Up: noopMigration, Down: noopMigration},
-		}
+		})
 
-		m := migrations
+		m := defaultMigrator.migrations
 		_, err := db.Model(&m).Insert()
 		assert.Nil(tt, err)
 
-		err = rollbackNamed(db, tmp, "456, 123")
+		err = defaultMigrator.rollbackNamed(ctx, db, []string{"456", "123"}, defaultReporter{})
 		assert.Nil(tt, err)
 
 		count, err := db.Model(&migration{}).Count()
@@ -128,14 +140,14 @@ func TestRollback(t *testing.T) {
 	t.Run(`runs "down" within a transaction if specified`, func(tt *testing.T) {
 		clearMigrations(tt, db)
 		resetMigrations(tt)
-		migrations = []migration{
+		setMigrations(tt, []migration{
 			{Name: "123", Up: noopMigration, Down: erringMigration, DisableTransaction: false, Batch: 1, CompletedAt: time.Now()},
-		}
+		})
 
-		_, err := db.Model(&migrations).Insert()
+		_, err := db.Model(&defaultMigrator.migrations).Insert()
 		assert.Nil(tt, err)
 
-		err = rollback(db, tmp)
+		err = defaultMigrator.rollback(ctx, db, defaultReporter{})
 		assert.EqualError(tt, err, "123: error")
 
 		assertTable(tt, db, "test_table", false)
@@ -144,14 +156,14 @@ func TestRollback(t *testing.T) {
 	t.Run(`doesn't run "down" within a transaction if specified`, func(tt *testing.T) {
 		clearMigrations(tt, db)
 		resetMigrations(tt)
-		migrations = []migration{
+		setMigrations(tt, []migration{
 			{Name: "123", Up: noopMigration, Down: erringMigration, DisableTransaction: true, Batch: 1, CompletedAt: time.Now()},
-		}
+		})
 
-		_, err := db.Model(&migrations).Insert()
+		_, err := db.Model(&defaultMigrator.migrations).Insert()
 		assert.Nil(tt, err)
 
-		err = rollback(db, tmp)
+		err = defaultMigrator.rollback(ctx, db, defaultReporter{})
 		assert.EqualError(tt, err, "123: error")
 
 		assertTable(tt, db, "test_table", true)
@@ -0,0 +1,69 @@
+package migrations
+
+import (
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+func ensureMigrationTables(db *pg.DB, lockID string) error {
+	exists, err := checkIfTableExists("migrations", db)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		err = createTable(&migration{}, db)
+		if err != nil {
+			return err
+		}
+	} else {
+		_, err = db.Exec(`ALTER TABLE migrations ADD COLUMN IF NOT EXISTS duration_ms bigint NOT NULL DEFAULT 0`)
+		if err != nil {
+			return err
+		}
+	}
+
+	exists, err = checkIfTableExists("migration_lock", db)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		err = createTable(&lock{}, db)
+		if err != nil {
+			return err
+		}
+	}
+
+	// each Migrator's lock lives in its own row, identified by its LockID, so
+	// that distinct Migrators can share the migration_lock table without
+	// contending for each other's locks
+	count, err := db.Model(&lock{}).Where("id = ?", lockID).Count()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		l := lock{ID: lockID, IsLocked: false}
+		_, err = db.Model(&l).Insert()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkIfTableExists(name string, db orm.DB) (bool, error) {
+	count, err := orm.NewQuery(db).
+		Table("information_schema.tables").
+		Where("table_name = ?", name).
+		Where("table_schema = current_schema").
+		Count()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func createTable(model interface{}, db *pg.DB) error {
+	opts := &orm.CreateTableOptions{IfNotExists: true}
+	return db.Model(model).CreateTable(opts)
+}
@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+)
+
+// Status reports the applied/pending state of every migration registered on
+// the default Migrator, including drift warnings for rows in the migrations
+// table that have no corresponding registration.
+func Status(db *pg.DB) ([]MigrationStatus, error) {
+	return defaultMigrator.Status(context.Background(), db)
+}
+
+// printStatus writes statuses to w as human-readable text, or as a JSON
+// array when format is "json".
+func printStatus(w io.Writer, statuses []MigrationStatus, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		return enc.Encode(statuses)
+	}
+
+	for _, s := range statuses {
+		state := "pending"
+		if s.Applied {
+			state = "applied"
+		}
+
+		line := fmt.Sprintf("%-8s %s", state, s.Name)
+		if s.Applied && s.CompletedAt != nil {
+			line += fmt.Sprintf(" (batch %d, completed %s, took %s)",
+				s.Batch, s.CompletedAt.Format(time.RFC3339), time.Duration(s.DurationMs)*time.Millisecond)
+		}
+		if s.Drift {
+			line += " [WARNING: not registered with this process]"
+		}
+
+		fmt.Fprintln(w, line)
+	}
+
+	return nil
+}
+
+func statusCmd(db *pg.DB, args []string) error {
+	format := ""
+	for _, a := range args {
+		if a == "--format=json" {
+			format = "json"
+		}
+	}
+
+	statuses, err := Status(db)
+	if err != nil {
+		return err
+	}
+
+	return printStatus(os.Stdout, statuses, format)
+}
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatus(t *testing.T) {
+	ctx := context.Background()
+	db := pg.Connect(&pg.Options{
+		Addr:     "localhost:5432",
+		User:     os.Getenv("TEST_DATABASE_USER"),
+		Database: os.Getenv("TEST_DATABASE_NAME"),
+	})
+
+	err := ensureMigrationTables(db, lockID)
+	require.Nil(t, err)
+
+	defer clearMigrations(t, db)
+	defer resetMigrations(t)
+
+	t.Run("reports applied and pending migrations", func(tt *testing.T) {
+		clearMigrations(tt, db)
+		resetMigrations(tt)
+		setMigrations(tt, []migration{
+			{Name: "123", Up: noopMigration, Down: noopMigration, Batch: 1, CompletedAt: time.Now(), DurationMs: 42},
+			{Name: "456", Up: noopMigration, Down: noopMigration},
+		})
+
+		_, err := db.Model(&[]migration{defaultMigrator.migrations[0]}).Insert()
+		assert.Nil(tt, err)
+
+		statuses, err := defaultMigrator.Status(ctx, db)
+		assert.Nil(tt, err)
+		require.Len(tt, statuses, 2)
+
+		assert.Equal(tt, "123", statuses[0].Name)
+		assert.True(tt, statuses[0].Applied)
+		require.NotNil(tt, statuses[0].CompletedAt)
+		assert.Equal(tt, int64(42), statuses[0].DurationMs)
+		assert.False(tt, statuses[0].Drift)
+
+		assert.Equal(tt, "456", statuses[1].Name)
+		assert.False(tt, statuses[1].Applied)
+		assert.Nil(tt, statuses[1].CompletedAt)
+	})
+
+	t.Run("flags completed migrations that aren't registered as drift", func(tt *testing.T) {
+		clearMigrations(tt, db)
+		resetMigrations(tt)
+		setMigrations(tt, []migration{
+			{Name: "123", Up: noopMigration, Down: noopMigration, Batch: 1, CompletedAt: time.Now()},
+		})
+
+		_, err := db.Model(&defaultMigrator.migrations).Insert()
+		assert.Nil(tt, err)
+
+		resetMigrations(tt)
+
+		statuses, err := defaultMigrator.Status(ctx, db)
+		assert.Nil(tt, err)
+		require.Len(tt, statuses, 1)
+		assert.Equal(tt, "123", statuses[0].Name)
+		assert.True(tt, statuses[0].Drift)
+	})
+}